@@ -0,0 +1,381 @@
+package zonefile
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stianwa/zonefile/rdata"
+)
+
+const (
+	defaultOwnerWidth = 20
+	defaultTTLWidth   = 4
+	defaultClassWidth = 4
+	defaultTypeWidth  = 10
+)
+
+// EncodeOptions controls how an Encoder formats entries.
+type EncodeOptions struct {
+	// OwnerWidth, TTLWidth, ClassWidth and TypeWidth set the
+	// column widths RR fields are left-aligned in, padded with
+	// spaces on the right, mirroring the
+	// historical "%-20s %-4s %-4s %-10s" layout. Zero uses the
+	// historical default for that column.
+	OwnerWidth int
+	TTLWidth   int
+	ClassWidth int
+	TypeWidth  int
+
+	// StripComments omits comment-only lines and the comments after
+	// RR data from the output. Comments after $ORIGIN, $TTL,
+	// $INCLUDE and $GENERATE directives are still written.
+	StripComments bool
+
+	// TTLSeconds emits every RR's TTL as a plain decimal number of
+	// seconds instead of reproducing its original unit form.
+	TTLSeconds bool
+
+	// Canonical produces the RFC 4034 section 6.2 canonical
+	// presentation format instead of the historical layout:
+	// lowercase, fully qualified owner names, lowercase and fully
+	// qualified domain names embedded in the RDATA of record types
+	// that carry one (NS, CNAME, PTR, DNAME, MX, SOA, SRV, RRSIG,
+	// NSEC, NAPTR; see canonicalRDataTokens), no $ORIGIN/$INCLUDE/
+	// $TTL directives or comments, an explicit TTL and class on
+	// every RR, and RRs sorted into canonical owner-name order.
+	// Names are qualified against the $ORIGIN that applies to each
+	// RR; a name with no $ORIGIN to qualify against, or an empty
+	// owner name (a blank owner the parser did not resolve, see
+	// ZoneParser.SetQualifyNames), is written as it is.
+	Canonical bool
+}
+
+// Encoder writes Entry values to an underlying io.Writer.
+type Encoder struct {
+	w    io.Writer
+	opts EncodeOptions
+
+	// origin and defaultTTL track the $ORIGIN/$TTL directives seen
+	// so far, used to qualify names and fill in TTLs that Canonical
+	// mode requires but a given RR may lack.
+	origin     string
+	defaultTTL string
+}
+
+// NewEncoder returns an Encoder writing to w. A nil opts uses the
+// historical formatting.
+func NewEncoder(w io.Writer, opts *EncodeOptions) *Encoder {
+	o := EncodeOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.OwnerWidth == 0 {
+		o.OwnerWidth = defaultOwnerWidth
+	}
+	if o.TTLWidth == 0 {
+		o.TTLWidth = defaultTTLWidth
+	}
+	if o.ClassWidth == 0 {
+		o.ClassWidth = defaultClassWidth
+	}
+	if o.TypeWidth == 0 {
+		o.TypeWidth = defaultTypeWidth
+	}
+
+	return &Encoder{w: w, opts: o}
+}
+
+// Encode writes a single entry. In Canonical mode, $ORIGIN and $TTL
+// directives update the encoder's state, and no $ORIGIN, $TTL or
+// $INCLUDE directive or comment is written, since canonical form
+// carries no directives; a
+// $GENERATE directive is expanded and each resulting RR is written in
+// its place, since canonical form has no directive to represent it
+// either. Callers wanting canonically sorted RRs (including the RRs
+// $GENERATE produces) should use EncodeAll instead, since Canonical
+// ordering requires seeing the whole set.
+func (e *Encoder) Encode(entry Entry) error {
+	switch v := entry.(type) {
+	case *Origin:
+		e.origin = v.DomainName
+		if e.opts.Canonical {
+			return nil
+		}
+		return e.writeString(v.String())
+	case *TTL:
+		e.defaultTTL = v.Value
+		if e.opts.Canonical {
+			return nil
+		}
+		return e.writeString(v.String())
+	case *Include:
+		if e.opts.Canonical {
+			return nil
+		}
+		return e.writeString(v.String())
+	case *Generate:
+		if e.opts.Canonical {
+			for _, rr := range v.Expand() {
+				if err := e.writeString(e.formatRR(rr)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return e.writeString(v.String())
+	case *Comment:
+		if e.opts.Canonical || e.opts.StripComments {
+			return nil
+		}
+		return e.writeString(v.String())
+	case *RR:
+		return e.writeString(e.formatRR(v))
+	default:
+		return fmt.Errorf("zonefile: unknown entry type %T", entry)
+	}
+}
+
+// EncodeAll writes every entry in entries. In Canonical mode it
+// first scans all entries for $ORIGIN/$TTL state, expanding any
+// $GENERATE directive into its RRs, then writes the combined parsed
+// and generated RRs, sorted into canonical owner-name order.
+func (e *Encoder) EncodeAll(entries []Entry) error {
+	if !e.opts.Canonical {
+		for _, entry := range entries {
+			if err := e.Encode(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var rrs []*RR
+	for _, entry := range entries {
+		switch v := entry.(type) {
+		case *Origin:
+			e.origin = v.DomainName
+		case *TTL:
+			e.defaultTTL = v.Value
+		case *RR:
+			rrs = append(rrs, v)
+		case *Generate:
+			rrs = append(rrs, v.Expand()...)
+		}
+	}
+
+	sortCanonical(rrs, e.ownerFQDN)
+	for _, rr := range rrs {
+		if err := e.writeString(e.formatRR(rr)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ownerFQDN qualifies rr's owner name against whichever $ORIGIN
+// applies: the one in effect when rr was parsed, or failing that
+// the most recent one this encoder has seen.
+func (e *Encoder) ownerFQDN(rr *RR) string {
+	return e.qualifyName(rr.DomainName, rr)
+}
+
+func (e *Encoder) formatRR(rr *RR) string {
+	if e.opts.Canonical {
+		return e.formatCanonicalRR(rr)
+	}
+
+	comment := func(c string) string {
+		if e.opts.StripComments {
+			return ""
+		}
+		return c
+	}
+
+	owner := rr.DomainName
+	if rr.OwnerBlank {
+		owner = ""
+	}
+	ttl := e.formatTTL(rr.TTL)
+
+	first := ""
+	if len(rr.RData) > 0 {
+		first = rr.RData[0].RData + comment(rr.RData[0].Comment)
+	}
+
+	format := fmt.Sprintf("%%-%ds %%-%ds %%-%ds %%-%ds %%s\n", e.opts.OwnerWidth, e.opts.TTLWidth, e.opts.ClassWidth, e.opts.TypeWidth)
+	s := fmt.Sprintf(format, owner, ttl, rr.Class, rr.Type, first)
+	if len(rr.RData) > 1 {
+		pad := " "
+		if n := strings.LastIndex(s, "("); n > -1 {
+			i := 1
+			for len(s) > i+n && isSpace(s[i+n]) {
+				i++
+			}
+			pad = strings.Repeat(" ", n+i)
+		}
+		for _, rd := range rr.RData[1:] {
+			s = s + pad + rd.RData + comment(rd.Comment) + "\n"
+		}
+	}
+
+	return s
+}
+
+func (e *Encoder) formatCanonicalRR(rr *RR) string {
+	name := strings.ToLower(e.ownerFQDN(rr))
+
+	ttlValue := rr.TTL
+	if ttlValue == "" {
+		ttlValue = e.defaultTTL
+	}
+	var ttlSecs uint32
+	if v, err := ParseTTL(ttlValue); err == nil {
+		ttlSecs = v
+	}
+
+	class := rr.Class
+	if class == "" {
+		class = "IN"
+	}
+
+	body := strings.Join(e.canonicalRDataTokens(rr), " ")
+
+	return fmt.Sprintf("%s %d %s %s %s\n", name, ttlSecs, class, rr.Type, body)
+}
+
+// canonicalRDataTokens returns rr's RData tokens with every embedded
+// domain name lowercased and qualified against rr's $ORIGIN, as RFC
+// 4034 section 6.2 requires of RR types that carry a domain name in
+// their RDATA (NS, CNAME, PTR, DNAME, MX, SOA, SRV, RRSIG, NSEC,
+// NAPTR). Types without typed decoding support, or tokens that fail
+// to decode, pass through unchanged, since there is then no
+// name-bearing field to qualify it could identify.
+func (e *Encoder) canonicalRDataTokens(rr *RR) []string {
+	tokens := rr.rdataTokens()
+
+	v, err := rdata.Decode(rr.Type, tokens)
+	if err != nil {
+		return tokens
+	}
+
+	qualify := func(name string) string {
+		return strings.ToLower(e.qualifyName(name, rr))
+	}
+
+	switch t := v.(type) {
+	case *rdata.NS:
+		t.Name = qualify(t.Name)
+	case *rdata.CNAME:
+		t.Name = qualify(t.Name)
+	case *rdata.PTR:
+		t.Name = qualify(t.Name)
+	case *rdata.DNAME:
+		t.Name = qualify(t.Name)
+	case *rdata.MX:
+		t.Exchange = qualify(t.Exchange)
+	case *rdata.SOA:
+		t.MName = qualify(t.MName)
+		t.RName = qualify(t.RName)
+	case *rdata.SRV:
+		t.Target = qualify(t.Target)
+	case *rdata.RRSIG:
+		t.SignerName = qualify(t.SignerName)
+	case *rdata.NSEC:
+		t.NextDomain = qualify(t.NextDomain)
+	case *rdata.NAPTR:
+		t.Replacement = qualify(t.Replacement)
+	default:
+		return tokens
+	}
+
+	return v.Encode()
+}
+
+// qualifyName resolves name against whichever $ORIGIN applies to rr:
+// the one in effect when it was parsed, or failing that the most
+// recent one this encoder has seen. It is the RDATA-name counterpart
+// of ownerFQDN.
+func (e *Encoder) qualifyName(name string, rr *RR) string {
+	origin := rr.Origin
+	if origin == "" {
+		origin = e.origin
+	}
+
+	switch {
+	case name == "@":
+		return origin
+	case name != "" && !strings.HasSuffix(name, ".") && origin != "":
+		return name + "." + origin
+	}
+
+	return name
+}
+
+// formatTTL reproduces ttl unless TTLSeconds is set, in which case
+// it is normalized to a plain decimal number of seconds.
+func (e *Encoder) formatTTL(ttl string) string {
+	if !e.opts.TTLSeconds || ttl == "" {
+		return ttl
+	}
+	secs, err := ParseTTL(ttl)
+	if err != nil {
+		return ttl
+	}
+
+	return strconv.FormatUint(uint64(secs), 10)
+}
+
+func (e *Encoder) writeString(s string) error {
+	_, err := io.WriteString(e.w, s)
+	return err
+}
+
+// sortCanonical stably sorts rrs into RFC 4034 section 6.1 canonical
+// owner-name order, then by type, using fqdn to resolve each RR's
+// fully qualified owner name.
+func sortCanonical(rrs []*RR, fqdn func(*RR) string) {
+	sort.SliceStable(rrs, func(i, j int) bool {
+		if c := compareCanonicalNames(fqdn(rrs[i]), fqdn(rrs[j])); c != 0 {
+			return c < 0
+		}
+		return rrs[i].Type < rrs[j].Type
+	})
+}
+
+// compareCanonicalNames orders a and b per RFC 4034 section 6.1:
+// names are compared label by label starting from the most
+// significant (rightmost) label; a name that is a proper suffix of
+// the other (an ancestor with fewer labels) sorts first.
+func compareCanonicalNames(a, b string) int {
+	la := canonicalLabels(a)
+	lb := canonicalLabels(b)
+
+	for i, j := len(la)-1, len(lb)-1; i >= 0 || j >= 0; i, j = i-1, j-1 {
+		if i < 0 {
+			return -1
+		}
+		if j < 0 {
+			return 1
+		}
+		if c := strings.Compare(la[i], lb[j]); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// canonicalLabels splits name into its lowercase labels, dropping a
+// trailing root dot.
+func canonicalLabels(name string) []string {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if name == "" {
+		return nil
+	}
+
+	return strings.Split(name, ".")
+}
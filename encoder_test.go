@@ -0,0 +1,130 @@
+package zonefile_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stianwa/zonefile"
+)
+
+// TestEncoderCanonicalOrder guards against comparing canonical owner
+// names left-to-right instead of from the most significant (rightmost)
+// label first: b.example.com. and a.z.example.com. share the suffix
+// "example.com.", so ordering must fall back to the first diverging
+// label, "b" vs "z", putting b.example.com. first.
+func TestEncoderCanonicalOrder(t *testing.T) {
+	data := `$ORIGIN example.com.
+$TTL 3600
+b IN A 192.0.2.1
+a.z IN A 192.0.2.2
+`
+	entries, err := zonefile.Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	var buf strings.Builder
+	enc := zonefile.NewEncoder(&buf, &zonefile.EncodeOptions{Canonical: true})
+	if err := enc.EncodeAll(entries); err != nil {
+		t.Fatalf("EncodeAll: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "b.example.com.") {
+		t.Errorf("lines[0] = %q, want b.example.com. first (rightmost-label order)", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "a.z.example.com.") {
+		t.Errorf("lines[1] = %q, want a.z.example.com. second", lines[1])
+	}
+	for _, l := range lines {
+		if !strings.Contains(l, "3600") || !strings.Contains(l, "IN") {
+			t.Errorf("line missing explicit TTL/class: %q", l)
+		}
+	}
+}
+
+// TestEncoderCanonicalQualifiesRDataNames guards against Canonical
+// mode lowercasing/qualifying only the owner name: RFC 4034 section
+// 6.2 requires the same of domain names embedded in the RDATA of
+// types like NS and CNAME, so an uppercase NS target must come out
+// lowercase, and a mixed-case, unqualified CNAME target lowercase and
+// fully qualified, not passed through as-is.
+func TestEncoderCanonicalQualifiesRDataNames(t *testing.T) {
+	data := `$ORIGIN example.com.
+www IN NS NS1.EXAMPLE.COM.
+www IN CNAME Target
+`
+	entries, err := zonefile.Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	var buf strings.Builder
+	enc := zonefile.NewEncoder(&buf, &zonefile.EncodeOptions{Canonical: true})
+	if err := enc.EncodeAll(entries); err != nil {
+		t.Fatalf("EncodeAll: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "ns1.example.com.") {
+		t.Errorf("NS target not lowercased/qualified: %q", out)
+	}
+	if strings.Contains(out, "NS1.EXAMPLE.COM.") {
+		t.Errorf("NS target still uppercase: %q", out)
+	}
+	if !strings.Contains(out, "target.example.com.") {
+		t.Errorf("CNAME target not lowercased/qualified: %q", out)
+	}
+}
+
+// TestEncoderCanonicalExpandsGenerate guards against Canonical mode
+// silently dropping $GENERATE directives instead of expanding them:
+// $GENERATE is the mechanism reverse zones rely on for their PTR
+// records, so canonicalizing one must not discard them.
+func TestEncoderCanonicalExpandsGenerate(t *testing.T) {
+	data := "$ORIGIN example.com.\n$GENERATE 1-3 host-$ A 192.0.2.$\n"
+	entries, err := zonefile.Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	var buf strings.Builder
+	enc := zonefile.NewEncoder(&buf, &zonefile.EncodeOptions{Canonical: true})
+	if err := enc.EncodeAll(entries); err != nil {
+		t.Fatalf("EncodeAll: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3 expanded RRs:\n%s", len(lines), buf.String())
+	}
+	for i, want := range []string{"host-1.example.com.", "host-2.example.com.", "host-3.example.com."} {
+		if !strings.HasPrefix(lines[i], want) {
+			t.Errorf("lines[%d] = %q, want prefix %q", i, lines[i], want)
+		}
+	}
+}
+
+func TestEncoderStripCommentsAndTTLSeconds(t *testing.T) {
+	entries, err := zonefile.Parse([]byte("www 1h IN A 192.0.2.1 ; comment\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	var buf strings.Builder
+	enc := zonefile.NewEncoder(&buf, &zonefile.EncodeOptions{StripComments: true, TTLSeconds: true})
+	if err := enc.EncodeAll(entries); err != nil {
+		t.Fatalf("EncodeAll: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "comment") {
+		t.Errorf("comment not stripped: %q", out)
+	}
+	if !strings.Contains(out, "3600") {
+		t.Errorf("TTL not normalized to seconds: %q", out)
+	}
+}
@@ -0,0 +1,260 @@
+package zonefile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Generate represents a $GENERATE directive:
+//
+//	$GENERATE range lhs [ttl] [class] type rhs
+//
+// as used by BIND to expand a range of similar RRs, most commonly
+// PTR records in reverse zones. The raw fields are kept as-is;
+// call Expand to produce the resulting RRs.
+type Generate struct {
+	// Range is the raw "start-stop[/step]" range specification.
+	Range string
+
+	// LHS is the owner name pattern, with $ substitutions.
+	LHS string
+
+	// TTL represents the record TTL if specified.
+	TTL string
+
+	// Class represents the record Class if specified.
+	Class string
+
+	// Type represents the record Type.
+	Type string
+
+	// RHS is the RData pattern, with $ substitutions.
+	RHS string
+
+	// Comment after the directive if any.
+	Comment string
+
+	// Origin is the $ORIGIN in effect when this Generate was parsed.
+	// Expand copies it into the Origin of each RR it produces, leaving
+	// their DomainName as generated; see RR.Origin and RR.FQDN.
+	Origin string
+
+	start, stop, step int
+}
+
+func (g *Generate) String() string {
+	fields := []string{"$GENERATE", g.Range, g.LHS}
+	if g.TTL != "" {
+		fields = append(fields, g.TTL)
+	}
+	if g.Class != "" {
+		fields = append(fields, g.Class)
+	}
+	fields = append(fields, g.Type, g.RHS)
+
+	return strings.Join(fields, " ") + g.Comment + "\n"
+}
+
+// Expand returns the RRs produced by iterating the range and
+// substituting $ in LHS and RHS for each step. It never returns an
+// error: the range and $ syntax are validated when the Generate is
+// parsed.
+func (g *Generate) Expand() []*RR {
+	var rrs []*RR
+	for i := g.start; i <= g.stop; i += g.step {
+		lhs, _ := expandGeneratePattern(g.LHS, i)
+		rhs, _ := expandGeneratePattern(g.RHS, i)
+		rrs = append(rrs, &RR{
+			DomainName: lhs,
+			Origin:     g.Origin,
+			TTL:        g.TTL,
+			Class:      g.Class,
+			Type:       g.Type,
+			RData:      []*RData{{RData: rhs}},
+		})
+	}
+
+	return rrs
+}
+
+// parseGenerate parses the fields of a $GENERATE directive line
+// (fields[0] is "$GENERATE") into a Generate.
+func parseGenerate(fields []string, comment string) (*Generate, error) {
+	if len(fields) < 4 {
+		return nil, fmt.Errorf("bad data for $GENERATE")
+	}
+
+	start, stop, step, err := parseGenerateRange(fields[1])
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Generate{
+		Range:   fields[1],
+		LHS:     fields[2],
+		Comment: comment,
+		start:   start,
+		stop:    stop,
+		step:    step,
+	}
+
+	rest := fields[3:]
+	if len(rest) > 2 && isDigit(rest[0][0]) {
+		g.TTL = rest[0]
+		rest = rest[1:]
+	}
+	if len(rest) > 2 && isClass(rest[0]) {
+		g.Class = rest[0]
+		rest = rest[1:]
+	}
+	if len(rest) > 2 && g.Class == "" && isClass(rest[0]) {
+		g.Class = rest[0]
+		rest = rest[1:]
+	}
+	if len(rest) < 2 {
+		return nil, fmt.Errorf("bad data for $GENERATE")
+	}
+	if !isType(rest[0]) {
+		return nil, fmt.Errorf("bad type for $GENERATE: %s", rest[0])
+	}
+	g.Type = rest[0]
+	g.RHS = strings.Join(rest[1:], " ")
+
+	if _, err := expandGeneratePattern(g.LHS, g.start); err != nil {
+		return nil, fmt.Errorf("bad lhs for $GENERATE: %s", err)
+	}
+	if _, err := expandGeneratePattern(g.RHS, g.start); err != nil {
+		return nil, fmt.Errorf("bad rhs for $GENERATE: %s", err)
+	}
+
+	return g, nil
+}
+
+// parseGenerateRange parses a "start-stop[/step]" range
+// specification. step defaults to 1 when absent.
+func parseGenerateRange(s string) (start, stop, step int, err error) {
+	rangeSpec, stepStr, hasStep := strings.Cut(s, "/")
+
+	bounds := strings.SplitN(rangeSpec, "-", 2)
+	if len(bounds) != 2 {
+		return 0, 0, 0, fmt.Errorf("bad range for $GENERATE: %s", s)
+	}
+
+	start, err = strconv.Atoi(bounds[0])
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("bad range start for $GENERATE: %s", s)
+	}
+	stop, err = strconv.Atoi(bounds[1])
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("bad range stop for $GENERATE: %s", s)
+	}
+
+	step = 1
+	if hasStep {
+		step, err = strconv.Atoi(stepStr)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("bad range step for $GENERATE: %s", s)
+		}
+	}
+
+	if stop < start {
+		return 0, 0, 0, fmt.Errorf("bad range for $GENERATE, stop before start: %s", s)
+	}
+	if step <= 0 {
+		return 0, 0, 0, fmt.Errorf("bad range step for $GENERATE, must be positive: %s", s)
+	}
+
+	return start, stop, step, nil
+}
+
+// expandGeneratePattern substitutes every $ in pattern for i,
+// following BIND $GENERATE syntax: \$ is a literal $, ${offset,
+// width,base} is i+offset formatted in base zero-padded to width,
+// and a bare $ is equivalent to ${0,0,d}.
+func expandGeneratePattern(pattern string, i int) (string, error) {
+	var sb strings.Builder
+
+	for j := 0; j < len(pattern); j++ {
+		c := pattern[j]
+		if c == '\\' && j+1 < len(pattern) && pattern[j+1] == '$' {
+			sb.WriteByte('$')
+			j++
+			continue
+		}
+		if c != '$' {
+			sb.WriteByte(c)
+			continue
+		}
+		if j+1 < len(pattern) && pattern[j+1] == '{' {
+			end := strings.IndexByte(pattern[j+1:], '}')
+			if end == -1 {
+				return "", fmt.Errorf("unterminated ${...} in pattern %q", pattern)
+			}
+			spec := pattern[j+2 : j+1+end]
+			s, err := formatGenerateSpec(spec, i)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(s)
+			j += end + 1
+			continue
+		}
+		sb.WriteString(strconv.Itoa(i))
+	}
+
+	return sb.String(), nil
+}
+
+// formatGenerateSpec formats i+offset for a "offset,width,base"
+// ${...} spec.
+func formatGenerateSpec(spec string, i int) (string, error) {
+	parts := strings.Split(spec, ",")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("bad ${%s}", spec)
+	}
+
+	offset, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return "", fmt.Errorf("bad offset in ${%s}", spec)
+	}
+	width, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return "", fmt.Errorf("bad width in ${%s}", spec)
+	}
+	base := strings.TrimSpace(parts[2])
+	v := i + offset
+
+	switch base {
+	case "d":
+		return fmt.Sprintf("%0*d", width, v), nil
+	case "o":
+		return fmt.Sprintf("%0*o", width, v), nil
+	case "x":
+		return fmt.Sprintf("%0*x", width, v), nil
+	case "X":
+		return fmt.Sprintf("%0*X", width, v), nil
+	case "n", "N":
+		return nibbleReverse(v, width, base == "N"), nil
+	default:
+		return "", fmt.Errorf("bad base %q in ${%s}", base, spec)
+	}
+}
+
+// nibbleReverse formats v as hex zero-padded to width, then reverses
+// the nibble order and separates them with dots, as used for
+// ip6.arpa PTR generation.
+func nibbleReverse(v, width int, upper bool) string {
+	format := "%0*x"
+	if upper {
+		format = "%0*X"
+	}
+	hex := fmt.Sprintf(format, width, v)
+
+	nibbles := make([]string, len(hex))
+	for i := 0; i < len(hex); i++ {
+		nibbles[len(hex)-1-i] = string(hex[i])
+	}
+
+	return strings.Join(nibbles, ".")
+}
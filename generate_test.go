@@ -0,0 +1,152 @@
+package zonefile_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stianwa/zonefile"
+)
+
+func TestGenerateExpand(t *testing.T) {
+	data := "$GENERATE 1-3 host-$ A 192.0.2.$\n"
+	entries, err := zonefile.Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	g, ok := entries[0].(*zonefile.Generate)
+	if !ok {
+		t.Fatalf("entry is %T, want *zonefile.Generate", entries[0])
+	}
+
+	rrs := g.Expand()
+	if len(rrs) != 3 {
+		t.Fatalf("got %d RRs, want 3", len(rrs))
+	}
+	for i, rr := range rrs {
+		wantName := fmt.Sprintf("host-%d", i+1)
+		wantData := fmt.Sprintf("192.0.2.%d", i+1)
+		if rr.DomainName != wantName {
+			t.Errorf("rr[%d].DomainName = %q, want %q", i, rr.DomainName, wantName)
+		}
+		if rr.Type != "A" {
+			t.Errorf("rr[%d].Type = %q, want A", i, rr.Type)
+		}
+		if len(rr.RData) != 1 || rr.RData[0].RData != wantData {
+			t.Errorf("rr[%d].RData = %+v, want %q", i, rr.RData, wantData)
+		}
+	}
+}
+
+func TestGenerateWidthFormat(t *testing.T) {
+	data := "$GENERATE 0-1 ${0,3,d} A 192.0.2.$\n"
+	entries, err := zonefile.Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	g := entries[0].(*zonefile.Generate)
+
+	rrs := g.Expand()
+	want := []string{"000", "001"}
+	for i, rr := range rrs {
+		if rr.DomainName != want[i] {
+			t.Errorf("rr[%d].DomainName = %q, want %q", i, rr.DomainName, want[i])
+		}
+	}
+}
+
+func TestGenerateBaseFormats(t *testing.T) {
+	for _, tc := range []struct {
+		base string
+		want []string
+	}{
+		{base: "o", want: []string{"012", "013"}},
+		{base: "x", want: []string{"00a", "00b"}},
+		{base: "X", want: []string{"00A", "00B"}},
+	} {
+		data := fmt.Sprintf("$GENERATE 10-11 ${0,3,%s} A 192.0.2.$\n", tc.base)
+		entries, err := zonefile.Parse([]byte(data))
+		if err != nil {
+			t.Fatalf("base %s: Parse: %v", tc.base, err)
+		}
+		g := entries[0].(*zonefile.Generate)
+
+		rrs := g.Expand()
+		for i, rr := range rrs {
+			if rr.DomainName != tc.want[i] {
+				t.Errorf("base %s: rr[%d].DomainName = %q, want %q", tc.base, i, rr.DomainName, tc.want[i])
+			}
+		}
+	}
+}
+
+// TestGenerateNibbleReverseFormat covers the n/N nibble-reversed hex
+// base, used to generate ip6.arpa PTR owner names from an IPv6
+// address: each hex digit of the offset value is emitted as its own
+// dot-separated label, in reverse order.
+func TestGenerateNibbleReverseFormat(t *testing.T) {
+	data := "$GENERATE 1-2 ${0,4,n} PTR host-$.example.com.\n"
+	entries, err := zonefile.Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	g := entries[0].(*zonefile.Generate)
+
+	rrs := g.Expand()
+	want := []string{"1.0.0.0", "2.0.0.0"}
+	for i, rr := range rrs {
+		if rr.DomainName != want[i] {
+			t.Errorf("rr[%d].DomainName = %q, want %q", i, rr.DomainName, want[i])
+		}
+	}
+
+	dataUpper := "$GENERATE 10-10 ${0,2,N} PTR host-$.example.com.\n"
+	entries, err = zonefile.Parse([]byte(dataUpper))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	g = entries[0].(*zonefile.Generate)
+
+	rrs = g.Expand()
+	if rrs[0].DomainName != "A.0" {
+		t.Errorf("rr[0].DomainName = %q, want %q", rrs[0].DomainName, "A.0")
+	}
+}
+
+func TestGenerateExpandOrigin(t *testing.T) {
+	data := "$ORIGIN example.com.\n$GENERATE 1-2 host-$ A 192.0.2.$\n"
+	entries, err := zonefile.Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	var g *zonefile.Generate
+	for _, e := range entries {
+		if gen, ok := e.(*zonefile.Generate); ok {
+			g = gen
+		}
+	}
+	if g == nil {
+		t.Fatalf("no Generate entry, entries: %#v", entries)
+	}
+	if g.Origin != "example.com." {
+		t.Fatalf("g.Origin = %q, want %q", g.Origin, "example.com.")
+	}
+
+	for i, rr := range g.Expand() {
+		if rr.Origin != "example.com." {
+			t.Errorf("rr[%d].Origin = %q, want %q", i, rr.Origin, "example.com.")
+		}
+	}
+}
+
+func TestGenerateBadRange(t *testing.T) {
+	if _, err := zonefile.Parse([]byte("$GENERATE 5-1 host-$ A 192.0.2.$\n")); err == nil {
+		t.Fatalf("expected error for stop < start")
+	}
+	if _, err := zonefile.Parse([]byte("$GENERATE 1-5/0 host-$ A 192.0.2.$\n")); err == nil {
+		t.Fatalf("expected error for step <= 0")
+	}
+}
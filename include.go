@@ -0,0 +1,131 @@
+package zonefile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ParseOptions controls how ParseFile resolves $INCLUDE
+// directives.
+type ParseOptions struct {
+	// AllowInclude opts in to following $INCLUDE directives on
+	// disk. It defaults to false: handing a parser untrusted
+	// zonefile bytes that then opens arbitrary filesystem paths is
+	// a disclosure risk, since a bad path surfaces the target
+	// file's content or existence through parse errors.
+	AllowInclude bool
+
+	// MaxIncludeDepth caps how many $INCLUDE files may be nested.
+	// Zero means DefaultMaxIncludeDepth.
+	MaxIncludeDepth int
+
+	// BaseDir resolves relative $INCLUDE filenames. Empty means
+	// the directory of path passed to ParseFile.
+	BaseDir string
+
+	// Origin is the $ORIGIN in effect before any $ORIGIN directive
+	// is seen in the file.
+	Origin string
+
+	// QualifyNames enables owner-name inheritance and $ORIGIN
+	// qualification; see ZoneParser.SetQualifyNames.
+	QualifyNames bool
+}
+
+// ParseFile parses the zonefile at path into an Entry slice,
+// following $INCLUDE directives on disk when opts.AllowInclude is
+// set. See ParseOptions.
+func ParseFile(path string, opts *ParseOptions) ([]Entry, error) {
+	if opts == nil {
+		opts = &ParseOptions{}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	baseDir := opts.BaseDir
+	if baseDir == "" {
+		baseDir = filepath.Dir(path)
+	}
+
+	p := NewZoneParser(f, opts.Origin, path)
+	p.SetIncludeAllowed(opts.AllowInclude)
+	p.SetQualifyNames(opts.QualifyNames)
+	p.SetBaseDir(baseDir)
+	if opts.MaxIncludeDepth > 0 {
+		p.SetMaxIncludeDepth(opts.MaxIncludeDepth)
+	}
+
+	if abs, err := filepath.Abs(path); err == nil {
+		p.includeChain = []string{abs}
+	}
+
+	var entries []Entry
+	for {
+		e, ok := p.Next()
+		if !ok {
+			break
+		}
+		entries = append(entries, e)
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// openInclude opens the file referenced by a $INCLUDE line and
+// returns a ZoneParser positioned to stream it, inheriting p's
+// $ORIGIN/$TTL/owner-name state (origin overridden by domainName if
+// given). $ORIGIN/$TTL directives seen in the included file do not
+// leak back into p, but the owner name of its last RR does: owner
+// inheritance is a per-line mechanical default, not directive state,
+// so Next carries it back into p.lastOwner once the child is done.
+func (p *ZoneParser) openInclude(filename, domainName string) (*ZoneParser, error) {
+	if p.depth+1 > p.maxIncludeDepth {
+		return nil, fmt.Errorf("$INCLUDE nesting exceeds max depth %d", p.maxIncludeDepth)
+	}
+
+	path := filename
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.baseDir, path)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, seen := range p.includeChain {
+		if seen == abs {
+			return nil, fmt.Errorf("$INCLUDE cycle detected: %s", strings.Join(append(p.includeChain, abs), " -> "))
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := p.origin
+	if domainName != "" {
+		origin = domainName
+	}
+
+	child := NewZoneParser(f, origin, path)
+	child.closer = f
+	child.includeAllowed = true
+	child.qualifyNames = p.qualifyNames
+	child.baseDir = filepath.Dir(path)
+	child.maxIncludeDepth = p.maxIncludeDepth
+	child.depth = p.depth + 1
+	child.includeChain = append(append([]string{}, p.includeChain...), abs)
+	child.defaultTTL = p.defaultTTL
+	child.lastOwner = p.lastOwner
+
+	return child, nil
+}
@@ -0,0 +1,145 @@
+package zonefile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stianwa/zonefile"
+)
+
+func TestParseFileFollowsInclude(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "child.zone"), []byte("host IN A 192.0.2.1\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	parentPath := filepath.Join(dir, "parent.zone")
+	if err := os.WriteFile(parentPath, []byte("$INCLUDE child.zone\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	entries, err := zonefile.ParseFile(parentPath, &zonefile.ParseOptions{AllowInclude: true})
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	var found *zonefile.RR
+	for _, e := range entries {
+		if rr, ok := e.(*zonefile.RR); ok {
+			found = rr
+		}
+	}
+	if found == nil || found.DomainName != "host" {
+		t.Fatalf("did not find included RR, entries: %#v", entries)
+	}
+}
+
+func TestParseFileIncludeNotAllowedByDefault(t *testing.T) {
+	dir := t.TempDir()
+	parentPath := filepath.Join(dir, "parent.zone")
+	if err := os.WriteFile(parentPath, []byte("$INCLUDE child.zone\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	entries, err := zonefile.ParseFile(parentPath, nil)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1 (unfollowed $INCLUDE)", len(entries))
+	}
+	if _, ok := entries[0].(*zonefile.Include); !ok {
+		t.Fatalf("entry is %T, want *zonefile.Include", entries[0])
+	}
+}
+
+func TestParseFileIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.zone"), []byte("$INCLUDE b.zone\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.zone"), []byte("$INCLUDE a.zone\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	_, err := zonefile.ParseFile(filepath.Join(dir, "a.zone"), &zonefile.ParseOptions{AllowInclude: true})
+	if err == nil {
+		t.Fatalf("expected include cycle error")
+	}
+}
+
+func TestParseFileOwnerInheritanceIntoInclude(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "child.zone"), []byte("\tIN A 192.0.2.1\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	parentPath := filepath.Join(dir, "parent.zone")
+	if err := os.WriteFile(parentPath, []byte("$ORIGIN example.com.\nhost IN A 192.0.2.2\n$INCLUDE child.zone\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	entries, err := zonefile.ParseFile(parentPath, &zonefile.ParseOptions{AllowInclude: true, QualifyNames: true})
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	var rrs []*zonefile.RR
+	for _, e := range entries {
+		if rr, ok := e.(*zonefile.RR); ok {
+			rrs = append(rrs, rr)
+		}
+	}
+	if len(rrs) != 2 {
+		t.Fatalf("got %d RRs, want 2, entries: %#v", len(rrs), entries)
+	}
+	if rrs[1].DomainName != "host.example.com." || !rrs[1].OwnerBlank {
+		t.Fatalf("included continuation line = %#v, want inherited owner host.example.com.", rrs[1])
+	}
+}
+
+func TestParseFileOwnerInheritanceOutOfInclude(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "child.zone"), []byte("host IN A 192.0.2.1\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	parentPath := filepath.Join(dir, "parent.zone")
+	if err := os.WriteFile(parentPath, []byte("$ORIGIN example.com.\n$INCLUDE child.zone\n\tIN A 192.0.2.2\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	entries, err := zonefile.ParseFile(parentPath, &zonefile.ParseOptions{AllowInclude: true, QualifyNames: true})
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+
+	var rrs []*zonefile.RR
+	for _, e := range entries {
+		if rr, ok := e.(*zonefile.RR); ok {
+			rrs = append(rrs, rr)
+		}
+	}
+	if len(rrs) != 2 {
+		t.Fatalf("got %d RRs, want 2, entries: %#v", len(rrs), entries)
+	}
+	if rrs[1].DomainName != "host.example.com." || !rrs[1].OwnerBlank {
+		t.Fatalf("continuation line after $INCLUDE = %#v, want inherited owner host.example.com.", rrs[1])
+	}
+}
+
+func TestParseFileMaxIncludeDepth(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.zone"), []byte("$INCLUDE b.zone\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.zone"), []byte("$INCLUDE c.zone\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "c.zone"), []byte("host IN A 192.0.2.1\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	_, err := zonefile.ParseFile(filepath.Join(dir, "a.zone"), &zonefile.ParseOptions{AllowInclude: true, MaxIncludeDepth: 1})
+	if err == nil {
+		t.Fatalf("expected max include depth error")
+	}
+}
@@ -0,0 +1,304 @@
+package zonefile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ZoneParser reads a zonefile line by line from an io.Reader and
+// yields one Entry at a time. Unlike Parse, it does not require the
+// whole file to be held in memory, which matters for large zones
+// (TLDs, DNSSEC-signed zones with millions of RRs).
+//
+// A ZoneParser is not safe for concurrent use.
+type ZoneParser struct {
+	scanner *bufio.Scanner
+
+	filename string
+
+	// origin holds the current effective $ORIGIN.
+	origin string
+
+	// defaultTTL holds the current effective $TTL.
+	defaultTTL string
+
+	// includeAllowed controls whether $INCLUDE directives are
+	// followed on disk. See ParseFile.
+	includeAllowed bool
+
+	// qualifyNames controls owner-name inheritance and $ORIGIN
+	// qualification. See SetQualifyNames.
+	qualifyNames bool
+
+	// baseDir resolves relative $INCLUDE filenames, and
+	// maxIncludeDepth/depth/includeChain guard against runaway or
+	// cyclic $INCLUDE chains. See ParseFile.
+	baseDir         string
+	maxIncludeDepth int
+	depth           int
+	includeChain    []string
+
+	// child is the ZoneParser currently streaming the file
+	// referenced by an $INCLUDE line, if any; closer closes the
+	// file it was opened from.
+	child  *ZoneParser
+	closer io.Closer
+
+	lineno int
+	rr     *RR
+
+	// lastOwner is the domain name of the last RR returned, used
+	// for owner-name inheritance.
+	lastOwner string
+
+	err  error
+	done bool
+}
+
+// DefaultMaxIncludeDepth is the default limit on nested $INCLUDE
+// files used by ParseFile and NewZoneParser.
+const DefaultMaxIncludeDepth = 7
+
+// NewZoneParser returns a ZoneParser reading from r. defaultOrigin
+// seeds the $ORIGIN in effect before any $ORIGIN directive is seen,
+// and filename is used to annotate error messages; both may be
+// empty.
+func NewZoneParser(r io.Reader, defaultOrigin, filename string) *ZoneParser {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	return &ZoneParser{
+		scanner:         scanner,
+		filename:        filename,
+		origin:          defaultOrigin,
+		maxIncludeDepth: DefaultMaxIncludeDepth,
+		rr:              &RR{},
+	}
+}
+
+// SetDefaultTTL sets the current default $TTL, as if a $TTL
+// directive with this value had just been parsed.
+func (p *ZoneParser) SetDefaultTTL(ttl string) {
+	p.defaultTTL = ttl
+}
+
+// SetIncludeAllowed controls whether $INCLUDE directives are
+// followed on disk. When enabled, a $INCLUDE line causes Next to
+// open and stream the referenced file's entries in place before
+// resuming the current file; see ParseOptions for the safety
+// implications.
+func (p *ZoneParser) SetIncludeAllowed(allowed bool) {
+	p.includeAllowed = allowed
+}
+
+// SetDefaultOrigin sets the current effective $ORIGIN, as if a
+// $ORIGIN directive with this value had just been parsed.
+func (p *ZoneParser) SetDefaultOrigin(origin string) {
+	p.origin = origin
+}
+
+// SetQualifyNames enables owner-name inheritance and $ORIGIN
+// qualification. When enabled, an RR line beginning with whitespace
+// inherits the previous RR's owner name (recorded via
+// RR.OwnerBlank), and every RR's DomainName is resolved to its FQDN
+// (see RR.FQDN) as it is returned by Next. Disabled by default for
+// backward compatibility: DomainName is left blank or relative as
+// found in the file.
+func (p *ZoneParser) SetQualifyNames(qualify bool) {
+	p.qualifyNames = qualify
+}
+
+// SetBaseDir sets the directory relative $INCLUDE filenames are
+// resolved against when SetIncludeAllowed(true) is in effect.
+func (p *ZoneParser) SetBaseDir(dir string) {
+	p.baseDir = dir
+}
+
+// SetMaxIncludeDepth overrides DefaultMaxIncludeDepth for how many
+// $INCLUDE files may be nested when SetIncludeAllowed(true) is in
+// effect.
+func (p *ZoneParser) SetMaxIncludeDepth(depth int) {
+	p.maxIncludeDepth = depth
+}
+
+// Err returns the first error encountered by Next, if any. It
+// should be checked after Next returns false.
+func (p *ZoneParser) Err() error {
+	return p.err
+}
+
+// Next reads and returns the next Entry from the underlying
+// reader. It returns false when there are no more entries, either
+// because the input is exhausted or because an error occurred; use
+// Err to distinguish the two.
+func (p *ZoneParser) Next() (Entry, bool) {
+	if p.err != nil || p.done {
+		return nil, false
+	}
+
+	if p.child != nil {
+		e, ok := p.child.Next()
+		if ok {
+			return e, true
+		}
+		err := p.child.Err()
+		if p.child.closer != nil {
+			p.child.closer.Close()
+		}
+		if p.child.lastOwner != "" {
+			p.lastOwner = p.child.lastOwner
+		}
+		p.child = nil
+		if err != nil {
+			p.err = err
+			p.done = true
+			return nil, false
+		}
+	}
+
+	for p.scanner.Scan() {
+		p.lineno++
+		origLine := p.scanner.Text()
+		if isEmptyLine(origLine) {
+			continue
+		}
+		line, cmnt := comment(origLine)
+
+		// An RR with type means we are not finished with the
+		// previous RR since we currently are within a
+		// parenthesis.
+		if p.rr.Type != "" {
+			line = strings.TrimSpace(line)
+			p.rr.RData = append(p.rr.RData, &RData{RData: line, Comment: cmnt})
+			if strings.HasSuffix(line, ")") {
+				return p.finishRR(), true
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			if cmnt != "" {
+				return &Comment{Comment: cmnt}, true
+			}
+			continue
+		}
+
+		switch fields[0] {
+		case "$ORIGIN":
+			if len(fields) != 2 {
+				return nil, p.fail("bad data for $ORIGIN")
+			}
+			p.origin = fields[1]
+			return &Origin{DomainName: fields[1], Comment: cmnt}, true
+		case "$INCLUDE":
+			if len(fields) < 2 || len(fields) > 3 {
+				return nil, p.fail("bad data for $INCLUDE")
+			}
+			domainName := ""
+			if len(fields) == 3 {
+				domainName = fields[2]
+			}
+			if p.includeAllowed {
+				child, err := p.openInclude(fields[1], domainName)
+				if err != nil {
+					return nil, p.fail("%s", err)
+				}
+				p.child = child
+			}
+			return &Include{FileName: fields[1], DomainName: domainName, Comment: cmnt}, true
+		case "$TTL":
+			if len(fields) != 2 {
+				return nil, p.fail("bad data for $TTL")
+			}
+			p.defaultTTL = fields[1]
+			return &TTL{Value: fields[1], Comment: cmnt}, true
+		case "$GENERATE":
+			g, err := parseGenerate(fields, cmnt)
+			if err != nil {
+				return nil, p.fail("%s", err)
+			}
+			g.Origin = p.origin
+			return g, true
+		default:
+			p.rr.Origin = p.origin
+			if !isSpace(line[0]) {
+				p.rr.DomainName = fields[0]
+				fields = fields[1:]
+			} else if p.qualifyNames {
+				p.rr.DomainName = p.lastOwner
+				p.rr.OwnerBlank = true
+			}
+			if len(fields) == 0 {
+				return nil, p.fail("bad data for RR")
+			}
+			// order of record TTL and class can be mixed
+			if len(fields) > 2 && isDigit(fields[0][0]) {
+				p.rr.TTL = fields[0]
+				fields = fields[1:]
+			}
+			if len(fields) > 2 && isClass(fields[0]) {
+				p.rr.Class = fields[0]
+				fields = fields[1:]
+			}
+			if len(fields) > 2 && p.rr.Class == "" && isClass(fields[0]) {
+				p.rr.Class = fields[0]
+				fields = fields[1:]
+			}
+
+			if len(fields) < 2 {
+				return nil, p.fail("bad data RDATA for RR")
+			}
+
+			if !isType(fields[0]) {
+				return nil, p.fail("bad type for RR: %s", fields[0])
+			}
+			p.rr.Type = fields[0]
+			fields = fields[1:]
+
+			rdata := &RData{RData: strings.Join(fields, " "), Comment: cmnt}
+			p.rr.RData = append(p.rr.RData, rdata)
+
+			if strings.Contains(rdata.RData, "(") && !strings.HasSuffix(rdata.RData, ")") {
+				// unclosed parenthesis, leave rr "open" for another iteration to close
+				continue
+			}
+			return p.finishRR(), true
+		}
+	}
+
+	p.done = true
+	if err := p.scanner.Err(); err != nil {
+		p.err = err
+	} else if p.rr.Type != "" {
+		p.err = p.fail0("unexpected end of file within parenthesis")
+	}
+
+	return nil, false
+}
+
+func (p *ZoneParser) finishRR() *RR {
+	rr := p.rr
+	if p.qualifyNames {
+		rr.DomainName = rr.FQDN()
+	}
+	p.lastOwner = rr.DomainName
+	p.rr = &RR{}
+	return rr
+}
+
+// fail records err as the parser's terminal error and always
+// returns false, so callers can `return nil, p.fail(...)`.
+func (p *ZoneParser) fail(format string, a ...interface{}) bool {
+	p.err = p.fail0(fmt.Sprintf(format, a...))
+	return false
+}
+
+func (p *ZoneParser) fail0(msg string) error {
+	if p.filename != "" {
+		return fmt.Errorf("%s: %s at line %d", p.filename, msg, p.lineno)
+	}
+	return fmt.Errorf("%s at line %d", msg, p.lineno)
+}
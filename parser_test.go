@@ -0,0 +1,75 @@
+package zonefile_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stianwa/zonefile"
+)
+
+func TestZoneParserNext(t *testing.T) {
+	data := `$ORIGIN example.com.
+$TTL 3600
+; a comment
+www IN A 192.0.2.1
+@ IN SOA ns1.example.com. hostmaster.example.com. (
+	2024010100
+	3600
+	900
+	604800
+	3600 )
+`
+	p := zonefile.NewZoneParser(strings.NewReader(data), "", "test.zone")
+
+	var got []zonefile.Entry
+	for {
+		e, ok := p.Next()
+		if !ok {
+			break
+		}
+		got = append(got, e)
+	}
+	if err := p.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 5 {
+		t.Fatalf("got %d entries, want 5: %#v", len(got), got)
+	}
+
+	www, ok := got[3].(*zonefile.RR)
+	if !ok {
+		t.Fatalf("entry 3 is %T, want *zonefile.RR", got[3])
+	}
+	if www.DomainName != "www" || www.Type != "A" {
+		t.Fatalf("unexpected RR: %+v", www)
+	}
+
+	soa, ok := got[4].(*zonefile.RR)
+	if !ok {
+		t.Fatalf("entry 4 is %T, want *zonefile.RR", got[4])
+	}
+	if soa.Type != "SOA" || len(soa.RData) != 6 {
+		t.Fatalf("multi-line RR not reassembled correctly: %+v", soa)
+	}
+}
+
+func TestParseUsesStreamingParser(t *testing.T) {
+	data := "www IN A 192.0.2.1\nwww IN A 192.0.2.2\n"
+	entries, err := zonefile.Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+}
+
+func TestZoneParserErr(t *testing.T) {
+	p := zonefile.NewZoneParser(strings.NewReader("$ORIGIN\n"), "", "")
+	if _, ok := p.Next(); ok {
+		t.Fatalf("expected Next to fail on bad $ORIGIN line")
+	}
+	if p.Err() == nil {
+		t.Fatalf("expected Err() to be set")
+	}
+}
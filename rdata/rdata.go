@@ -0,0 +1,194 @@
+// Package rdata decodes and encodes the RData of common DNS record
+// types. It is used by (*zonefile.RR).Decode and (*zonefile.RR).Encode
+// to turn the opaque, textual RData zonefile carries into typed Go
+// values and back.
+package rdata
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TypedRData is implemented by every decoded record type. Encode
+// returns the wire-format text tokens for the type, in the order
+// they should appear on a zonefile line.
+type TypedRData interface {
+	Encode() []string
+}
+
+// Decode decodes tokens, the whitespace/quote-aware token stream of
+// an RR's RData, into a TypedRData for rrtype. It returns an error
+// for a rrtype it doesn't know, or for tokens that don't match the
+// type's format.
+func Decode(rrtype string, tokens []string) (TypedRData, error) {
+	decoder, ok := decoders[strings.ToUpper(rrtype)]
+	if !ok {
+		return nil, fmt.Errorf("rdata: no typed decoder for RR type %s", rrtype)
+	}
+
+	return decoder(tokens)
+}
+
+var decoders = map[string]func([]string) (TypedRData, error){
+	"A":      decodeA,
+	"AAAA":   decodeAAAA,
+	"NS":     decodeNS,
+	"CNAME":  decodeCNAME,
+	"PTR":    decodePTR,
+	"DNAME":  decodeDNAME,
+	"MX":     decodeMX,
+	"SOA":    decodeSOA,
+	"TXT":    decodeTXT,
+	"SRV":    decodeSRV,
+	"CAA":    decodeCAA,
+	"TLSA":   decodeTLSA,
+	"SSHFP":  decodeSSHFP,
+	"DS":     decodeDS,
+	"DNSKEY": decodeDNSKEY,
+	"RRSIG":  decodeRRSIG,
+	"NSEC":   decodeNSEC,
+	"NSEC3":  decodeNSEC3,
+	"NAPTR":  decodeNAPTR,
+}
+
+// Tokenize splits s on whitespace, treating a "quoted string" -
+// including one containing embedded spaces or a backslash escape -
+// as a single token. It is used to turn the reassembled, multi-line
+// RData of an RR back into a logical token stream.
+func Tokenize(s string) []string {
+	var tokens []string
+	var cur strings.Builder
+	inQuotes := false
+	has := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			cur.WriteByte(c)
+			cur.WriteByte(s[i+1])
+			i++
+			has = true
+		case c == '"':
+			cur.WriteByte(c)
+			inQuotes = !inQuotes
+			has = true
+		case (c == ' ' || c == '\t') && !inQuotes:
+			if has {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+				has = false
+			}
+		default:
+			cur.WriteByte(c)
+			has = true
+		}
+	}
+	if has {
+		tokens = append(tokens, cur.String())
+	}
+
+	return tokens
+}
+
+func needTokens(tokens []string, n int, rrtype string) error {
+	if len(tokens) != n {
+		return fmt.Errorf("rdata: %s wants %d fields, got %d", rrtype, n, len(tokens))
+	}
+	return nil
+}
+
+func atLeastTokens(tokens []string, n int, rrtype string) error {
+	if len(tokens) < n {
+		return fmt.Errorf("rdata: %s wants at least %d fields, got %d", rrtype, n, len(tokens))
+	}
+	return nil
+}
+
+func parseUint8(s, field string) (uint8, error) {
+	v, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("rdata: bad %s %q: %v", field, s, err)
+	}
+	return uint8(v), nil
+}
+
+func parseUint16(s, field string) (uint16, error) {
+	v, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("rdata: bad %s %q: %v", field, s, err)
+	}
+	return uint16(v), nil
+}
+
+func parseUint32(s, field string) (uint32, error) {
+	v, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("rdata: bad %s %q: %v", field, s, err)
+	}
+	return uint32(v), nil
+}
+
+// unquote turns a zonefile character-string token, which may or may
+// not be quoted, into its content: \" and \\ are literal, and
+// \DDD is a decimal byte escape.
+func unquote(tok string) (string, error) {
+	if len(tok) >= 2 && tok[0] == '"' && tok[len(tok)-1] == '"' {
+		tok = tok[1 : len(tok)-1]
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(tok); i++ {
+		c := tok[i]
+		if c != '\\' {
+			sb.WriteByte(c)
+			continue
+		}
+		if i+1 >= len(tok) {
+			return "", fmt.Errorf("rdata: trailing backslash in %q", tok)
+		}
+		i++
+		if i+2 < len(tok) && isDigit(tok[i]) && isDigit(tok[i+1]) && isDigit(tok[i+2]) {
+			n, err := strconv.Atoi(tok[i : i+3])
+			if err != nil || n > 255 {
+				return "", fmt.Errorf("rdata: bad \\DDD escape in %q", tok)
+			}
+			sb.WriteByte(byte(n))
+			i += 2
+			continue
+		}
+		sb.WriteByte(tok[i])
+	}
+
+	return sb.String(), nil
+}
+
+// quote produces a quoted zonefile character-string for s, the
+// inverse of unquote: '"' and '\\' are backslash-escaped, and any
+// other non-printable or non-ASCII byte is emitted as a \DDD
+// decimal escape so it can never be read back as a raw control
+// character (e.g. a literal newline splitting the line it's on).
+func quote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&sb, "\\%03d", c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	sb.WriteByte('"')
+
+	return sb.String()
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
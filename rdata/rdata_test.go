@@ -0,0 +1,89 @@
+package rdata_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stianwa/zonefile/rdata"
+)
+
+func TestDecodeA(t *testing.T) {
+	v, err := rdata.Decode("A", []string{"192.0.2.1"})
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	a, ok := v.(*rdata.A)
+	if !ok {
+		t.Fatalf("got %T, want *rdata.A", v)
+	}
+	if !a.IP.Equal(net.ParseIP("192.0.2.1")) {
+		t.Errorf("IP = %v, want 192.0.2.1", a.IP)
+	}
+	if got := a.Encode(); len(got) != 1 || got[0] != "192.0.2.1" {
+		t.Errorf("Encode() = %v, want [192.0.2.1]", got)
+	}
+}
+
+func TestDecodeMX(t *testing.T) {
+	v, err := rdata.Decode("MX", []string{"10", "mail.example.com."})
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	mx := v.(*rdata.MX)
+	if mx.Preference != 10 || mx.Exchange != "mail.example.com." {
+		t.Errorf("got %+v", mx)
+	}
+}
+
+func TestDecodeSOA(t *testing.T) {
+	tokens := []string{"ns1.example.com.", "hostmaster.example.com.", "2024010100", "3600", "900", "604800", "3600"}
+	v, err := rdata.Decode("SOA", tokens)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	soa := v.(*rdata.SOA)
+	if soa.Serial != 2024010100 || soa.Minimum != 3600 {
+		t.Errorf("got %+v", soa)
+	}
+	if encoded := soa.Encode(); len(encoded) != 7 {
+		t.Fatalf("Encode() = %v, want 7 fields", encoded)
+	}
+}
+
+// TestTXTRoundTripWithControlByte guards against quote() writing a raw
+// control byte back into the wire format instead of re-escaping it as
+// \DDD, which would otherwise let a decoded literal newline split a
+// zonefile line in two when the Encoder writes it back out.
+func TestTXTRoundTripWithControlByte(t *testing.T) {
+	v, err := rdata.Decode("TXT", []string{`"a\010b"`})
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	txt := v.(*rdata.TXT)
+	if txt.Strings[0] != "a\nb" {
+		t.Fatalf("Strings[0] = %q, want %q", txt.Strings[0], "a\nb")
+	}
+
+	encoded := txt.Encode()
+	if len(encoded) != 1 {
+		t.Fatalf("Encode() = %v, want 1 token", encoded)
+	}
+	if strings.ContainsRune(encoded[0], '\n') {
+		t.Fatalf("Encode() re-emitted a raw newline: %q", encoded[0])
+	}
+
+	roundTripped, err := rdata.Decode("TXT", encoded)
+	if err != nil {
+		t.Fatalf("Decode (round trip): %v", err)
+	}
+	if roundTripped.(*rdata.TXT).Strings[0] != "a\nb" {
+		t.Fatalf("round trip mismatch: %+v", roundTripped)
+	}
+}
+
+func TestDecodeUnknownType(t *testing.T) {
+	if _, err := rdata.Decode("BOGUS", []string{"x"}); err == nil {
+		t.Fatalf("expected error for unknown RR type")
+	}
+}
@@ -0,0 +1,628 @@
+package rdata
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// A is the RData of an A record.
+type A struct {
+	IP net.IP
+}
+
+func decodeA(tokens []string) (TypedRData, error) {
+	if err := needTokens(tokens, 1, "A"); err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(tokens[0]).To4()
+	if ip == nil {
+		return nil, fmt.Errorf("rdata: bad A address %q", tokens[0])
+	}
+	return &A{IP: ip}, nil
+}
+
+// Encode implements TypedRData.
+func (r *A) Encode() []string { return []string{r.IP.String()} }
+
+// AAAA is the RData of an AAAA record.
+type AAAA struct {
+	IP net.IP
+}
+
+func decodeAAAA(tokens []string) (TypedRData, error) {
+	if err := needTokens(tokens, 1, "AAAA"); err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(tokens[0])
+	if ip == nil || ip.To4() != nil {
+		return nil, fmt.Errorf("rdata: bad AAAA address %q", tokens[0])
+	}
+	return &AAAA{IP: ip}, nil
+}
+
+// Encode implements TypedRData.
+func (r *AAAA) Encode() []string { return []string{r.IP.String()} }
+
+// NS is the RData of a NS record.
+type NS struct{ Name string }
+
+func decodeNS(tokens []string) (TypedRData, error) {
+	if err := needTokens(tokens, 1, "NS"); err != nil {
+		return nil, err
+	}
+	return &NS{Name: tokens[0]}, nil
+}
+
+// Encode implements TypedRData.
+func (r *NS) Encode() []string { return []string{r.Name} }
+
+// CNAME is the RData of a CNAME record.
+type CNAME struct{ Name string }
+
+func decodeCNAME(tokens []string) (TypedRData, error) {
+	if err := needTokens(tokens, 1, "CNAME"); err != nil {
+		return nil, err
+	}
+	return &CNAME{Name: tokens[0]}, nil
+}
+
+// Encode implements TypedRData.
+func (r *CNAME) Encode() []string { return []string{r.Name} }
+
+// PTR is the RData of a PTR record.
+type PTR struct{ Name string }
+
+func decodePTR(tokens []string) (TypedRData, error) {
+	if err := needTokens(tokens, 1, "PTR"); err != nil {
+		return nil, err
+	}
+	return &PTR{Name: tokens[0]}, nil
+}
+
+// Encode implements TypedRData.
+func (r *PTR) Encode() []string { return []string{r.Name} }
+
+// DNAME is the RData of a DNAME record.
+type DNAME struct{ Name string }
+
+func decodeDNAME(tokens []string) (TypedRData, error) {
+	if err := needTokens(tokens, 1, "DNAME"); err != nil {
+		return nil, err
+	}
+	return &DNAME{Name: tokens[0]}, nil
+}
+
+// Encode implements TypedRData.
+func (r *DNAME) Encode() []string { return []string{r.Name} }
+
+// MX is the RData of a MX record.
+type MX struct {
+	Preference uint16
+	Exchange   string
+}
+
+func decodeMX(tokens []string) (TypedRData, error) {
+	if err := needTokens(tokens, 2, "MX"); err != nil {
+		return nil, err
+	}
+	pref, err := parseUint16(tokens[0], "MX preference")
+	if err != nil {
+		return nil, err
+	}
+	return &MX{Preference: pref, Exchange: tokens[1]}, nil
+}
+
+// Encode implements TypedRData.
+func (r *MX) Encode() []string {
+	return []string{fmt.Sprintf("%d", r.Preference), r.Exchange}
+}
+
+// SOA is the RData of a SOA record.
+type SOA struct {
+	MName   string
+	RName   string
+	Serial  uint32
+	Refresh uint32
+	Retry   uint32
+	Expire  uint32
+	Minimum uint32
+}
+
+func decodeSOA(tokens []string) (TypedRData, error) {
+	if err := needTokens(tokens, 7, "SOA"); err != nil {
+		return nil, err
+	}
+	serial, err := parseUint32(tokens[2], "SOA serial")
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := parseUint32(tokens[3], "SOA refresh")
+	if err != nil {
+		return nil, err
+	}
+	retry, err := parseUint32(tokens[4], "SOA retry")
+	if err != nil {
+		return nil, err
+	}
+	expire, err := parseUint32(tokens[5], "SOA expire")
+	if err != nil {
+		return nil, err
+	}
+	minimum, err := parseUint32(tokens[6], "SOA minimum")
+	if err != nil {
+		return nil, err
+	}
+	return &SOA{
+		MName:   tokens[0],
+		RName:   tokens[1],
+		Serial:  serial,
+		Refresh: refresh,
+		Retry:   retry,
+		Expire:  expire,
+		Minimum: minimum,
+	}, nil
+}
+
+// Encode implements TypedRData.
+func (r *SOA) Encode() []string {
+	return []string{
+		r.MName, r.RName,
+		fmt.Sprintf("%d", r.Serial),
+		fmt.Sprintf("%d", r.Refresh),
+		fmt.Sprintf("%d", r.Retry),
+		fmt.Sprintf("%d", r.Expire),
+		fmt.Sprintf("%d", r.Minimum),
+	}
+}
+
+// TXT is the RData of a TXT record: a list of character-strings.
+type TXT struct {
+	Strings []string
+}
+
+func decodeTXT(tokens []string) (TypedRData, error) {
+	if err := atLeastTokens(tokens, 1, "TXT"); err != nil {
+		return nil, err
+	}
+	strs := make([]string, len(tokens))
+	for i, t := range tokens {
+		s, err := unquote(t)
+		if err != nil {
+			return nil, err
+		}
+		strs[i] = s
+	}
+	return &TXT{Strings: strs}, nil
+}
+
+// Encode implements TypedRData.
+func (r *TXT) Encode() []string {
+	out := make([]string, len(r.Strings))
+	for i, s := range r.Strings {
+		out[i] = quote(s)
+	}
+	return out
+}
+
+// SRV is the RData of a SRV record.
+type SRV struct {
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	Target   string
+}
+
+func decodeSRV(tokens []string) (TypedRData, error) {
+	if err := needTokens(tokens, 4, "SRV"); err != nil {
+		return nil, err
+	}
+	priority, err := parseUint16(tokens[0], "SRV priority")
+	if err != nil {
+		return nil, err
+	}
+	weight, err := parseUint16(tokens[1], "SRV weight")
+	if err != nil {
+		return nil, err
+	}
+	port, err := parseUint16(tokens[2], "SRV port")
+	if err != nil {
+		return nil, err
+	}
+	return &SRV{Priority: priority, Weight: weight, Port: port, Target: tokens[3]}, nil
+}
+
+// Encode implements TypedRData.
+func (r *SRV) Encode() []string {
+	return []string{
+		fmt.Sprintf("%d", r.Priority),
+		fmt.Sprintf("%d", r.Weight),
+		fmt.Sprintf("%d", r.Port),
+		r.Target,
+	}
+}
+
+// CAA is the RData of a CAA record.
+type CAA struct {
+	Flag  uint8
+	Tag   string
+	Value string
+}
+
+func decodeCAA(tokens []string) (TypedRData, error) {
+	if err := needTokens(tokens, 3, "CAA"); err != nil {
+		return nil, err
+	}
+	flag, err := parseUint8(tokens[0], "CAA flag")
+	if err != nil {
+		return nil, err
+	}
+	value, err := unquote(tokens[2])
+	if err != nil {
+		return nil, err
+	}
+	return &CAA{Flag: flag, Tag: tokens[1], Value: value}, nil
+}
+
+// Encode implements TypedRData.
+func (r *CAA) Encode() []string {
+	return []string{fmt.Sprintf("%d", r.Flag), r.Tag, quote(r.Value)}
+}
+
+// TLSA is the RData of a TLSA record.
+type TLSA struct {
+	Usage        uint8
+	Selector     uint8
+	MatchingType uint8
+	Certificate  []byte
+}
+
+func decodeTLSA(tokens []string) (TypedRData, error) {
+	if err := atLeastTokens(tokens, 4, "TLSA"); err != nil {
+		return nil, err
+	}
+	usage, err := parseUint8(tokens[0], "TLSA usage")
+	if err != nil {
+		return nil, err
+	}
+	selector, err := parseUint8(tokens[1], "TLSA selector")
+	if err != nil {
+		return nil, err
+	}
+	matchingType, err := parseUint8(tokens[2], "TLSA matching type")
+	if err != nil {
+		return nil, err
+	}
+	cert, err := hex.DecodeString(strings.Join(tokens[3:], ""))
+	if err != nil {
+		return nil, fmt.Errorf("rdata: bad TLSA certificate: %v", err)
+	}
+	return &TLSA{Usage: usage, Selector: selector, MatchingType: matchingType, Certificate: cert}, nil
+}
+
+// Encode implements TypedRData.
+func (r *TLSA) Encode() []string {
+	return []string{
+		fmt.Sprintf("%d", r.Usage),
+		fmt.Sprintf("%d", r.Selector),
+		fmt.Sprintf("%d", r.MatchingType),
+		hex.EncodeToString(r.Certificate),
+	}
+}
+
+// SSHFP is the RData of a SSHFP record.
+type SSHFP struct {
+	Algorithm   uint8
+	FPType      uint8
+	FingerPrint []byte
+}
+
+func decodeSSHFP(tokens []string) (TypedRData, error) {
+	if err := atLeastTokens(tokens, 3, "SSHFP"); err != nil {
+		return nil, err
+	}
+	algo, err := parseUint8(tokens[0], "SSHFP algorithm")
+	if err != nil {
+		return nil, err
+	}
+	fptype, err := parseUint8(tokens[1], "SSHFP fingerprint type")
+	if err != nil {
+		return nil, err
+	}
+	fp, err := hex.DecodeString(strings.Join(tokens[2:], ""))
+	if err != nil {
+		return nil, fmt.Errorf("rdata: bad SSHFP fingerprint: %v", err)
+	}
+	return &SSHFP{Algorithm: algo, FPType: fptype, FingerPrint: fp}, nil
+}
+
+// Encode implements TypedRData.
+func (r *SSHFP) Encode() []string {
+	return []string{
+		fmt.Sprintf("%d", r.Algorithm),
+		fmt.Sprintf("%d", r.FPType),
+		hex.EncodeToString(r.FingerPrint),
+	}
+}
+
+// DS is the RData of a DS record.
+type DS struct {
+	KeyTag     uint16
+	Algorithm  uint8
+	DigestType uint8
+	Digest     []byte
+}
+
+func decodeDS(tokens []string) (TypedRData, error) {
+	if err := atLeastTokens(tokens, 4, "DS"); err != nil {
+		return nil, err
+	}
+	keyTag, err := parseUint16(tokens[0], "DS key tag")
+	if err != nil {
+		return nil, err
+	}
+	algo, err := parseUint8(tokens[1], "DS algorithm")
+	if err != nil {
+		return nil, err
+	}
+	digestType, err := parseUint8(tokens[2], "DS digest type")
+	if err != nil {
+		return nil, err
+	}
+	digest, err := hex.DecodeString(strings.Join(tokens[3:], ""))
+	if err != nil {
+		return nil, fmt.Errorf("rdata: bad DS digest: %v", err)
+	}
+	return &DS{KeyTag: keyTag, Algorithm: algo, DigestType: digestType, Digest: digest}, nil
+}
+
+// Encode implements TypedRData.
+func (r *DS) Encode() []string {
+	return []string{
+		fmt.Sprintf("%d", r.KeyTag),
+		fmt.Sprintf("%d", r.Algorithm),
+		fmt.Sprintf("%d", r.DigestType),
+		hex.EncodeToString(r.Digest),
+	}
+}
+
+// DNSKEY is the RData of a DNSKEY record.
+type DNSKEY struct {
+	Flags     uint16
+	Protocol  uint8
+	Algorithm uint8
+	PublicKey []byte
+}
+
+func decodeDNSKEY(tokens []string) (TypedRData, error) {
+	if err := atLeastTokens(tokens, 4, "DNSKEY"); err != nil {
+		return nil, err
+	}
+	flags, err := parseUint16(tokens[0], "DNSKEY flags")
+	if err != nil {
+		return nil, err
+	}
+	protocol, err := parseUint8(tokens[1], "DNSKEY protocol")
+	if err != nil {
+		return nil, err
+	}
+	algo, err := parseUint8(tokens[2], "DNSKEY algorithm")
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.Join(tokens[3:], ""))
+	if err != nil {
+		return nil, fmt.Errorf("rdata: bad DNSKEY public key: %v", err)
+	}
+	return &DNSKEY{Flags: flags, Protocol: protocol, Algorithm: algo, PublicKey: key}, nil
+}
+
+// Encode implements TypedRData.
+func (r *DNSKEY) Encode() []string {
+	return []string{
+		fmt.Sprintf("%d", r.Flags),
+		fmt.Sprintf("%d", r.Protocol),
+		fmt.Sprintf("%d", r.Algorithm),
+		base64.StdEncoding.EncodeToString(r.PublicKey),
+	}
+}
+
+const rrsigTimeLayout = "20060102150405"
+
+// RRSIG is the RData of a RRSIG record.
+type RRSIG struct {
+	TypeCovered string
+	Algorithm   uint8
+	Labels      uint8
+	OriginalTTL uint32
+	Expiration  time.Time
+	Inception   time.Time
+	KeyTag      uint16
+	SignerName  string
+	Signature   []byte
+}
+
+func decodeRRSIG(tokens []string) (TypedRData, error) {
+	if err := atLeastTokens(tokens, 9, "RRSIG"); err != nil {
+		return nil, err
+	}
+	algo, err := parseUint8(tokens[1], "RRSIG algorithm")
+	if err != nil {
+		return nil, err
+	}
+	labels, err := parseUint8(tokens[2], "RRSIG labels")
+	if err != nil {
+		return nil, err
+	}
+	origTTL, err := parseUint32(tokens[3], "RRSIG original TTL")
+	if err != nil {
+		return nil, err
+	}
+	expiration, err := time.Parse(rrsigTimeLayout, tokens[4])
+	if err != nil {
+		return nil, fmt.Errorf("rdata: bad RRSIG expiration %q: %v", tokens[4], err)
+	}
+	inception, err := time.Parse(rrsigTimeLayout, tokens[5])
+	if err != nil {
+		return nil, fmt.Errorf("rdata: bad RRSIG inception %q: %v", tokens[5], err)
+	}
+	keyTag, err := parseUint16(tokens[6], "RRSIG key tag")
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.Join(tokens[8:], ""))
+	if err != nil {
+		return nil, fmt.Errorf("rdata: bad RRSIG signature: %v", err)
+	}
+	return &RRSIG{
+		TypeCovered: tokens[0],
+		Algorithm:   algo,
+		Labels:      labels,
+		OriginalTTL: origTTL,
+		Expiration:  expiration.UTC(),
+		Inception:   inception.UTC(),
+		KeyTag:      keyTag,
+		SignerName:  tokens[7],
+		Signature:   sig,
+	}, nil
+}
+
+// Encode implements TypedRData.
+func (r *RRSIG) Encode() []string {
+	return []string{
+		r.TypeCovered,
+		fmt.Sprintf("%d", r.Algorithm),
+		fmt.Sprintf("%d", r.Labels),
+		fmt.Sprintf("%d", r.OriginalTTL),
+		r.Expiration.UTC().Format(rrsigTimeLayout),
+		r.Inception.UTC().Format(rrsigTimeLayout),
+		fmt.Sprintf("%d", r.KeyTag),
+		r.SignerName,
+		base64.StdEncoding.EncodeToString(r.Signature),
+	}
+}
+
+// NSEC is the RData of a NSEC record.
+type NSEC struct {
+	NextDomain  string
+	TypeBitMaps []string
+}
+
+func decodeNSEC(tokens []string) (TypedRData, error) {
+	if err := atLeastTokens(tokens, 1, "NSEC"); err != nil {
+		return nil, err
+	}
+	return &NSEC{NextDomain: tokens[0], TypeBitMaps: append([]string{}, tokens[1:]...)}, nil
+}
+
+// Encode implements TypedRData.
+func (r *NSEC) Encode() []string {
+	return append([]string{r.NextDomain}, r.TypeBitMaps...)
+}
+
+// NSEC3 is the RData of a NSEC3 record.
+type NSEC3 struct {
+	HashAlgorithm       uint8
+	Flags               uint8
+	Iterations          uint16
+	Salt                string
+	NextHashedOwnerName string
+	TypeBitMaps         []string
+}
+
+func decodeNSEC3(tokens []string) (TypedRData, error) {
+	if err := atLeastTokens(tokens, 5, "NSEC3"); err != nil {
+		return nil, err
+	}
+	hashAlgo, err := parseUint8(tokens[0], "NSEC3 hash algorithm")
+	if err != nil {
+		return nil, err
+	}
+	flags, err := parseUint8(tokens[1], "NSEC3 flags")
+	if err != nil {
+		return nil, err
+	}
+	iterations, err := parseUint16(tokens[2], "NSEC3 iterations")
+	if err != nil {
+		return nil, err
+	}
+	return &NSEC3{
+		HashAlgorithm:       hashAlgo,
+		Flags:               flags,
+		Iterations:          iterations,
+		Salt:                tokens[3],
+		NextHashedOwnerName: tokens[4],
+		TypeBitMaps:         append([]string{}, tokens[5:]...),
+	}, nil
+}
+
+// Encode implements TypedRData.
+func (r *NSEC3) Encode() []string {
+	out := []string{
+		fmt.Sprintf("%d", r.HashAlgorithm),
+		fmt.Sprintf("%d", r.Flags),
+		fmt.Sprintf("%d", r.Iterations),
+		r.Salt,
+		r.NextHashedOwnerName,
+	}
+	return append(out, r.TypeBitMaps...)
+}
+
+// NAPTR is the RData of a NAPTR record.
+type NAPTR struct {
+	Order       uint16
+	Preference  uint16
+	Flags       string
+	Services    string
+	Regexp      string
+	Replacement string
+}
+
+func decodeNAPTR(tokens []string) (TypedRData, error) {
+	if err := needTokens(tokens, 6, "NAPTR"); err != nil {
+		return nil, err
+	}
+	order, err := parseUint16(tokens[0], "NAPTR order")
+	if err != nil {
+		return nil, err
+	}
+	preference, err := parseUint16(tokens[1], "NAPTR preference")
+	if err != nil {
+		return nil, err
+	}
+	flags, err := unquote(tokens[2])
+	if err != nil {
+		return nil, err
+	}
+	services, err := unquote(tokens[3])
+	if err != nil {
+		return nil, err
+	}
+	regexp, err := unquote(tokens[4])
+	if err != nil {
+		return nil, err
+	}
+	return &NAPTR{
+		Order:       order,
+		Preference:  preference,
+		Flags:       flags,
+		Services:    services,
+		Regexp:      regexp,
+		Replacement: tokens[5],
+	}, nil
+}
+
+// Encode implements TypedRData.
+func (r *NAPTR) Encode() []string {
+	return []string{
+		fmt.Sprintf("%d", r.Order),
+		fmt.Sprintf("%d", r.Preference),
+		quote(r.Flags),
+		quote(r.Services),
+		quote(r.Regexp),
+		r.Replacement,
+	}
+}
@@ -0,0 +1,43 @@
+package zonefile
+
+import (
+	"strings"
+
+	"github.com/stianwa/zonefile/rdata"
+)
+
+// Decode reassembles r's (possibly multi-line, parenthesized) RData
+// into a single token stream and decodes it into a typed value for
+// r.Type, e.g. *rdata.A for an A record. It returns an error if
+// r.Type has no typed decoder or the tokens don't match its format.
+func (r *RR) Decode() (rdata.TypedRData, error) {
+	return rdata.Decode(r.Type, r.rdataTokens())
+}
+
+// Encode replaces r.RData with the wire-format tokens of v, encoded
+// onto a single line. Any existing comments and line breaks in
+// r.RData are discarded.
+func (r *RR) Encode(v rdata.TypedRData) {
+	r.RData = []*RData{{RData: strings.Join(v.Encode(), " ")}}
+}
+
+// rdataTokens joins every line of r.RData and tokenizes the result,
+// dropping the "(" / ")" punctuation used to span RData across
+// lines.
+func (r *RR) rdataTokens() []string {
+	lines := make([]string, len(r.RData))
+	for i, rd := range r.RData {
+		lines[i] = rd.RData
+	}
+
+	tokens := rdata.Tokenize(strings.Join(lines, " "))
+	out := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if t == "(" || t == ")" {
+			continue
+		}
+		out = append(out, t)
+	}
+
+	return out
+}
@@ -0,0 +1,66 @@
+package zonefile_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stianwa/zonefile"
+)
+
+func TestQualifyNames(t *testing.T) {
+	data := `$ORIGIN example.com.
+www IN A 192.0.2.1
+    IN A 192.0.2.2
+@ IN MX 10 mail
+`
+	p := zonefile.NewZoneParser(strings.NewReader(data), "", "")
+	p.SetQualifyNames(true)
+
+	var rrs []*zonefile.RR
+	for {
+		e, ok := p.Next()
+		if !ok {
+			break
+		}
+		if rr, ok := e.(*zonefile.RR); ok {
+			rrs = append(rrs, rr)
+		}
+	}
+	if err := p.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rrs) != 3 {
+		t.Fatalf("got %d RRs, want 3", len(rrs))
+	}
+
+	if rrs[0].DomainName != "www.example.com." {
+		t.Errorf("rrs[0].DomainName = %q, want %q", rrs[0].DomainName, "www.example.com.")
+	}
+	if !rrs[1].OwnerBlank {
+		t.Errorf("rrs[1].OwnerBlank = false, want true")
+	}
+	if rrs[1].DomainName != "www.example.com." {
+		t.Errorf("rrs[1].DomainName (inherited) = %q, want %q", rrs[1].DomainName, "www.example.com.")
+	}
+	if rrs[2].DomainName != "example.com." {
+		t.Errorf("rrs[2].DomainName (@) = %q, want %q", rrs[2].DomainName, "example.com.")
+	}
+}
+
+func TestRRFQDNAndIsRelative(t *testing.T) {
+	rr := &zonefile.RR{DomainName: "www", Origin: "example.com."}
+	if !rr.IsRelative() {
+		t.Errorf("IsRelative() = false, want true for %q", rr.DomainName)
+	}
+	if got, want := rr.FQDN(), "www.example.com."; got != want {
+		t.Errorf("FQDN() = %q, want %q", got, want)
+	}
+
+	fqdn := &zonefile.RR{DomainName: "www.example.com."}
+	if fqdn.IsRelative() {
+		t.Errorf("IsRelative() = true, want false for already-qualified name")
+	}
+	if got, want := fqdn.FQDN(), "www.example.com."; got != want {
+		t.Errorf("FQDN() = %q, want %q", got, want)
+	}
+}
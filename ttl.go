@@ -0,0 +1,74 @@
+package zonefile
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ParseTTL parses a zonefile TTL value into seconds. It accepts a
+// plain decimal number of seconds, or the BIND-style unit notation
+// of RFC 2308 appendix A: one or more <digits><unit> pairs, unit
+// being one of s/S (seconds), m/M (minutes), h/H (hours), d/D
+// (days) or w/W (weeks), e.g. "5M", "1h30m", "2W".
+func ParseTTL(s string) (uint32, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty TTL")
+	}
+
+	allDigits := true
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			allDigits = false
+			break
+		}
+	}
+	if allDigits {
+		v, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("bad TTL %q: %v", s, err)
+		}
+		return uint32(v), nil
+	}
+
+	var total uint64
+	for i := 0; i < len(s); {
+		start := i
+		for i < len(s) && isDigit(s[i]) {
+			i++
+		}
+		if i == start {
+			return 0, fmt.Errorf("bad TTL %q", s)
+		}
+		n, err := strconv.ParseUint(s[start:i], 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("bad TTL %q: %v", s, err)
+		}
+		if i >= len(s) {
+			return 0, fmt.Errorf("bad TTL %q: missing unit", s)
+		}
+
+		var mul uint64
+		switch s[i] {
+		case 's', 'S':
+			mul = 1
+		case 'm', 'M':
+			mul = 60
+		case 'h', 'H':
+			mul = 3600
+		case 'd', 'D':
+			mul = 86400
+		case 'w', 'W':
+			mul = 604800
+		default:
+			return 0, fmt.Errorf("bad TTL %q: unknown unit %q", s, s[i])
+		}
+		i++
+		total += n * mul
+	}
+
+	if total > 1<<32-1 {
+		return 0, fmt.Errorf("bad TTL %q: overflow", s)
+	}
+
+	return uint32(total), nil
+}
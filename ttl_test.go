@@ -0,0 +1,39 @@
+package zonefile_test
+
+import (
+	"testing"
+
+	"github.com/stianwa/zonefile"
+)
+
+func TestParseTTL(t *testing.T) {
+	cases := []struct {
+		in   string
+		want uint32
+	}{
+		{"3600", 3600},
+		{"5m", 300},
+		{"1h", 3600},
+		{"1d", 86400},
+		{"2w", 1209600},
+		{"1h30m", 5400},
+	}
+	for _, c := range cases {
+		got, err := zonefile.ParseTTL(c.in)
+		if err != nil {
+			t.Errorf("ParseTTL(%q): %v", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ParseTTL(%q) = %d, want %d", c.in, got, c.want)
+		}
+	}
+}
+
+func TestParseTTLErrors(t *testing.T) {
+	for _, in := range []string{"", "abc", "5x"} {
+		if _, err := zonefile.ParseTTL(in); err == nil {
+			t.Errorf("ParseTTL(%q): expected error", in)
+		}
+	}
+}
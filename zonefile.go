@@ -11,6 +11,7 @@
 package zonefile
 
 import (
+	"bytes"
 	"fmt"
 	"strings"
 )
@@ -88,6 +89,16 @@ type RR struct {
 	// DomainName represents the records domain name
 	DomainName string
 
+	// OwnerBlank is true when the line this RR was parsed from had
+	// no owner name of its own and DomainName was inherited from
+	// the previous RR. Only set when the parser is configured to
+	// qualify names; see ZoneParser.SetQualifyNames.
+	OwnerBlank bool
+
+	// Origin is the $ORIGIN in effect when this RR was parsed, used
+	// by FQDN to qualify a relative DomainName.
+	Origin string
+
 	// TTL represents the record TTL if specified
 	TTL string
 
@@ -104,13 +115,38 @@ type RR struct {
 	RData []*RData
 }
 
+// IsRelative reports whether DomainName is not already fully
+// qualified, i.e. it is "@" or another non-empty name that does not
+// end with ".". An empty DomainName is not relative.
+func (r *RR) IsRelative() bool {
+	return r.DomainName != "" && !strings.HasSuffix(r.DomainName, ".")
+}
+
+// FQDN returns DomainName qualified against Origin: "@" expands to
+// Origin, a name not ending in "." has Origin appended, and an
+// already-qualified or empty DomainName is returned unchanged.
+func (r *RR) FQDN() string {
+	if r.DomainName == "@" {
+		return r.Origin
+	}
+	if !r.IsRelative() || r.Origin == "" {
+		return r.DomainName
+	}
+
+	return r.DomainName + "." + r.Origin
+}
+
 func (r *RR) String() string {
 	first := ""
 	if len(r.RData) > 0 {
 		first = r.RData[0].RData + r.RData[0].Comment
 	}
 
-	s := fmt.Sprintf("%-20s %-4s %-4s %-10s %s\n", r.DomainName, r.TTL, r.Class, r.Type, first)
+	owner := r.DomainName
+	if r.OwnerBlank {
+		owner = ""
+	}
+	s := fmt.Sprintf("%-20s %-4s %-4s %-10s %s\n", owner, r.TTL, r.Class, r.Type, first)
 	if len(r.RData) > 1 {
 		pad := " "
 		if n := strings.LastIndex(s, "("); n > -1 {
@@ -136,97 +172,22 @@ type Entry interface {
 // Parse parses zonefile data into an Entry slice. One entry per
 // line. Lines containing only spaces or CR are ignored and will not
 // be reproduced when printing entries.
+//
+// Parse reads the whole file into memory before returning; for
+// large zones, use NewZoneParser to stream entries one at a time.
 func Parse(data []byte) ([]Entry, error) {
-	var entries []Entry
+	p := NewZoneParser(bytes.NewReader(data), "", "")
 
-	rr := &RR{}
-	for lineno, origLine := range strings.Split(string(data), "\n") {
-		if isEmptyLine(origLine) {
-			continue
-		}
-		line, comment := comment(origLine)
-		// An RR with type means we are not finished with the
-		// previous RR since we currently are within a
-		// parenthesis
-		if rr.Type != "" {
-			line = strings.TrimSpace(line)
-			rr.RData = append(rr.RData, &RData{RData: line, Comment: comment})
-			if strings.HasSuffix(line, ")") {
-				entries = append(entries, rr)
-				rr = &RR{}
-			}
-			continue
-		}
-		fields := strings.Fields(line)
-		if len(fields) == 0 {
-			if comment != "" {
-				entries = append(entries, &Comment{Comment: comment})
-			}
-			continue
-		}
-		switch fields[0] {
-		case "$ORIGIN":
-			if len(fields) != 2 {
-				return nil, fmt.Errorf("bad data for $ORIGIN at line %d", lineno)
-			}
-			entries = append(entries, &Origin{DomainName: fields[1], Comment: comment})
-		case "$INCLUDE":
-			if len(fields) < 2 || len(fields) > 3 {
-				return nil, fmt.Errorf("bad data for $INCLUDE at line %d", lineno)
-			}
-			domainName := ""
-			if len(fields) == 3 {
-				domainName = fields[2]
-			}
-
-			entries = append(entries, &Include{FileName: fields[1], DomainName: domainName, Comment: comment})
-		case "$TTL":
-			if len(fields) != 2 {
-				return nil, fmt.Errorf("bad data for $TTL at line %d", lineno)
-			}
-			entries = append(entries, &TTL{Value: fields[1], Comment: comment})
-		default:
-			if !isSpace(line[0]) {
-				rr.DomainName = fields[0]
-				fields = fields[1:]
-			}
-			if len(fields) == 0 {
-				return nil, fmt.Errorf("bad data for RR at line %d", lineno)
-			}
-			// order of record TTL and class can be mixed
-			if len(fields) > 2 && isDigit(fields[0][0]) {
-				rr.TTL = fields[0]
-				fields = fields[1:]
-			}
-			if len(fields) > 2 && isClass(fields[0]) {
-				rr.Class = fields[0]
-				fields = fields[1:]
-			}
-			if len(fields) > 2 && rr.Class == "" && isClass(fields[0]) {
-				rr.Class = fields[0]
-				fields = fields[1:]
-			}
-
-			if len(fields) < 2 {
-				return nil, fmt.Errorf("bad data RDATA for RR at line %d", lineno)
-			}
-
-			if !isType(fields[0]) {
-				return nil, fmt.Errorf("bad type for RR at line %d: %s", lineno, fields[0])
-			}
-			rr.Type = fields[0]
-			fields = fields[1:]
-
-			rdata := &RData{RData: strings.Join(fields, " "), Comment: comment}
-			rr.RData = append(rr.RData, rdata)
-
-			if strings.Contains(rdata.RData, "(") && !strings.HasSuffix(rdata.RData, ")") {
-				// unclosed parenthesis, leave rr "open" for another iteration to close
-				continue
-			}
-			entries = append(entries, rr)
-			rr = &RR{}
+	var entries []Entry
+	for {
+		e, ok := p.Next()
+		if !ok {
+			break
 		}
+		entries = append(entries, e)
+	}
+	if err := p.Err(); err != nil {
+		return nil, err
 	}
 
 	return entries, nil